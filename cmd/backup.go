@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/go-faster/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/iyear/tdl/core/util/fsutil"
+	"github.com/iyear/tdl/pkg/helpers"
+	"github.com/iyear/tdl/pkg/iostreams"
+	"github.com/iyear/tdl/pkg/kv"
+)
+
+func NewBackup() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "backup <file>",
+		Short:   "Back up the whole KV storage (every namespace) to a file",
+		GroupID: groupAccount.ID,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+			reporter := iostreams.From(cmd.Context()).NewReporter()
+
+			if fsutil.PathExists(path) {
+				ok, err := helpers.Confirm(cmd.Context(), fmt.Sprintf("%s already exists and will be overwritten. Continue?", path))
+				if err != nil {
+					return errors.Wrap(err, "confirm overwrite")
+				}
+				if !ok {
+					return errors.New("backup cancelled")
+				}
+			}
+
+			meta, err := kv.From(cmd.Context()).MigrateTo()
+			if err != nil {
+				reporter.Error(path, err)
+				return errors.Wrap(err, "dump storage")
+			}
+
+			raw, err := json.Marshal(meta)
+			if err != nil {
+				reporter.Error(path, err)
+				return errors.Wrap(err, "marshal backup")
+			}
+
+			if err = os.WriteFile(path, raw, 0o600); err != nil {
+				reporter.Error(path, err)
+				return errors.Wrap(err, "write backup file")
+			}
+
+			reporter.Done(path)
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-faster/errors"
+	"github.com/gotd/td/telegram"
+	"github.com/gotd/td/tg"
+	"github.com/spf13/cobra"
+
+	"github.com/iyear/tdl/pkg/helpers"
+	"github.com/iyear/tdl/pkg/iostreams"
+	"github.com/iyear/tdl/pkg/kv"
+)
+
+// parseChatID parses a raw numeric chat ID argument, shared by chat and
+// forward subcommands that take a chat/peer ID directly rather than
+// resolving a username.
+func parseChatID(raw string) (int64, error) {
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid chat id %q", raw)
+	}
+	return id, nil
+}
+
+func NewChat() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "chat",
+		Short:   "Inspect and manage Telegram chats",
+		GroupID: groupTools.ID,
+	}
+
+	cmd.AddCommand(NewChatList(), NewChatDelete())
+
+	return cmd
+}
+
+func NewChatList() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List chats (dialogs) for the current account",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return tRun(cmd.Context(), func(ctx context.Context, c *telegram.Client, kvd kv.KV) error {
+				dialogs, err := c.API().MessagesGetDialogs(ctx, &tg.MessagesGetDialogsRequest{
+					OffsetPeer: &tg.InputPeerEmpty{},
+					Limit:      100,
+				})
+				if err != nil {
+					return errors.Wrap(err, "get dialogs")
+				}
+
+				for _, chat := range dialogs.GetChats() {
+					fmt.Fprintf(cmd.OutOrStdout(), "%d\t%s\n", chat.GetID(), chatTitle(chat))
+				}
+				return nil
+			})
+		},
+	}
+}
+
+func NewChatDelete() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <chat-id>",
+		Short: "Delete a chat's message history for the current account",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseChatID(args[0])
+			if err != nil {
+				return err
+			}
+
+			ok, err := helpers.Confirm(cmd.Context(), fmt.Sprintf("This will permanently delete message history for chat %d. Continue?", id))
+			if err != nil {
+				return errors.Wrap(err, "confirm delete")
+			}
+			if !ok {
+				return errors.New("delete cancelled")
+			}
+
+			reporter := iostreams.From(cmd.Context()).NewReporter()
+			taskID := fmt.Sprintf("chat/%d", id)
+
+			err = tRun(cmd.Context(), func(ctx context.Context, c *telegram.Client, kvd kv.KV) error {
+				_, err := c.API().MessagesDeleteHistory(ctx, &tg.MessagesDeleteHistoryRequest{
+					Peer:   &tg.InputPeerChat{ChatID: id},
+					Revoke: true,
+				})
+				return errors.Wrap(err, "delete history")
+			})
+			if err != nil {
+				reporter.Error(taskID, err)
+				return err
+			}
+
+			reporter.Done(taskID)
+			return nil
+		},
+	}
+}
+
+// chatTitle returns a human-readable label for a dialog's chat entity.
+func chatTitle(chat tg.ChatClass) string {
+	switch c := chat.(type) {
+	case *tg.Chat:
+		return c.Title
+	case *tg.Channel:
+		return c.Title
+	default:
+		return fmt.Sprintf("chat#%d", chat.GetID())
+	}
+}
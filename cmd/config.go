@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+
+	"github.com/go-faster/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/iyear/tdl/pkg/config"
+	"github.com/iyear/tdl/pkg/iostreams"
+)
+
+func NewConfig() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "config",
+		Short:   "Inspect and modify tdl's config file",
+		GroupID: groupTools.ID,
+	}
+
+	cmd.AddCommand(NewConfigShow(), NewConfigEdit(), NewConfigSet(), NewConfigUnset(), NewConfigProfiles())
+
+	return cmd
+}
+
+func NewConfigShow() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the config resolved for a profile (defaults + profile overrides)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile, _ := cmd.Flags().GetString(flagProfile)
+
+			path, file, err := loadConfigFile(cmd)
+			if err != nil {
+				return err
+			}
+
+			resolved := config.Resolve(file, profile)
+
+			raw, err := yaml.Marshal(resolved)
+			if err != nil {
+				return errors.Wrap(err, "marshal config")
+			}
+
+			label := profile
+			if label == "" {
+				label = "defaults"
+			}
+			fmt.Fprintf(iostreams.From(cmd.Context()).Out, "# %s (profile: %s)\n%s", path, label, raw)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagProfile, "", "profile to resolve, instead of the shared defaults")
+	return cmd
+}
+
+func NewConfigEdit() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Open the config file in $EDITOR",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, file, err := loadConfigFile(cmd)
+			if err != nil {
+				return err
+			}
+			if err = config.Save(path, file); err != nil {
+				return errors.Wrap(err, "create config file")
+			}
+
+			editor := os.Getenv("EDITOR")
+			if editor == "" {
+				return errors.New("$EDITOR is not set")
+			}
+
+			c := exec.Command(editor, path)
+			c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+			return errors.Wrap(c.Run(), "run editor")
+		},
+	}
+}
+
+func NewConfigSet() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a value in the config file, optionally scoped to a profile",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile, _ := cmd.Flags().GetString(flagProfile)
+
+			path, file, err := loadConfigFile(cmd)
+			if err != nil {
+				return err
+			}
+
+			target := &file.Defaults
+			if profile != "" {
+				if file.Profiles == nil {
+					file.Profiles = map[string]config.Profile{}
+				}
+				p := file.Profiles[profile]
+				target = &p
+			}
+
+			if err = setConfigKey(target, args[0], args[1]); err != nil {
+				return err
+			}
+
+			// target is a copy of the map entry when scoped to a profile, so
+			// write it back before saving, not after (defer would run too late).
+			if profile != "" {
+				file.Profiles[profile] = *target
+			}
+
+			return errors.Wrap(config.Save(path, file), "save config file")
+		},
+	}
+
+	cmd.Flags().String(flagProfile, "", "profile to set the value on, instead of the shared defaults")
+	return cmd
+}
+
+func NewConfigUnset() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a value from the config file, optionally scoped to a profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile, _ := cmd.Flags().GetString(flagProfile)
+
+			path, file, err := loadConfigFile(cmd)
+			if err != nil {
+				return err
+			}
+
+			target := &file.Defaults
+			if profile != "" {
+				p := file.Profiles[profile]
+				target = &p
+			}
+
+			if err = setConfigKey(target, args[0], ""); err != nil {
+				return err
+			}
+
+			// see NewConfigSet: write the profile copy back before saving.
+			if profile != "" {
+				file.Profiles[profile] = *target
+			}
+
+			return errors.Wrap(config.Save(path, file), "save config file")
+		},
+	}
+
+	cmd.Flags().String(flagProfile, "", "profile to unset the value on, instead of the shared defaults")
+	return cmd
+}
+
+func NewConfigProfiles() *cobra.Command {
+	return &cobra.Command{
+		Use:   "profiles",
+		Short: "List configured profile names",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, file, err := loadConfigFile(cmd)
+			if err != nil {
+				return err
+			}
+
+			names := make([]string, 0, len(file.Profiles))
+			for name := range file.Profiles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				fmt.Fprintln(iostreams.From(cmd.Context()).Out, name)
+			}
+			return nil
+		},
+	}
+}
+
+func loadConfigFile(cmd *cobra.Command) (string, *config.File, error) {
+	path, _ := cmd.Flags().GetString(flagConfig)
+	if path == "" {
+		p, err := config.Path()
+		if err != nil {
+			return "", nil, errors.Wrap(err, "resolve default config path")
+		}
+		path = p
+	}
+
+	file, err := config.Load(path)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "load config file %s", path)
+	}
+	return path, file, nil
+}
+
+// setConfigKey applies value to the field of p named by key (the same names
+// accepted by --storage/--proxy/etc., minus "storage" which isn't settable
+// this way since it's itself a key=value map).
+func setConfigKey(p *config.Profile, key, value string) error {
+	switch key {
+	case "proxy":
+		p.Proxy = value
+	case "ntp":
+		p.NTP = value
+	case "part-size":
+		return setConfigInt(&p.PartSize, value)
+	case "threads":
+		return setConfigInt(&p.Threads, value)
+	case "limit":
+		return setConfigInt(&p.Limit, value)
+	case "pool-size":
+		return setConfigIntPtr(&p.PoolSize, value)
+	default:
+		return errors.Errorf("unknown config key: %s", key)
+	}
+	return nil
+}
+
+func setConfigInt(dst *int, value string) error {
+	if value == "" {
+		*dst = 0
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return errors.Wrapf(err, "invalid integer value %q", value)
+	}
+	*dst = n
+	return nil
+}
+
+// setConfigIntPtr is like setConfigInt, but for fields where an explicit 0
+// is meaningful (see config.Profile.PoolSize): "unset" clears the pointer
+// entirely instead of writing a 0 that Resolve would then treat as set.
+func setConfigIntPtr(dst **int, value string) error {
+	if value == "" {
+		*dst = nil
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return errors.Wrapf(err, "invalid integer value %q", value)
+	}
+	*dst = &n
+	return nil
+}
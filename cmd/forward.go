@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/go-faster/errors"
+	"github.com/gotd/td/telegram"
+	"github.com/gotd/td/tg"
+	"github.com/spf13/cobra"
+
+	"github.com/iyear/tdl/pkg/helpers"
+	"github.com/iyear/tdl/pkg/iostreams"
+	"github.com/iyear/tdl/pkg/kv"
+)
+
+const (
+	flagForwardFrom      = "from"
+	flagForwardTo        = "to"
+	flagForwardMessages  = "messages"
+	flagForwardDeleteSrc = "delete"
+)
+
+func NewForward() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "forward",
+		Short:   "Forward messages from one chat to another",
+		GroupID: groupTools.ID,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, _ := cmd.Flags().GetString(flagForwardFrom)
+			to, _ := cmd.Flags().GetString(flagForwardTo)
+			messages, _ := cmd.Flags().GetIntSlice(flagForwardMessages)
+			deleteSrc, _ := cmd.Flags().GetBool(flagForwardDeleteSrc)
+
+			fromID, err := parseChatID(from)
+			if err != nil {
+				return err
+			}
+			toID, err := parseChatID(to)
+			if err != nil {
+				return err
+			}
+
+			if deleteSrc {
+				ok, err := helpers.Confirm(cmd.Context(),
+					fmt.Sprintf("This will permanently delete %d message(s) from chat %d after forwarding. Continue?", len(messages), fromID))
+				if err != nil {
+					return errors.Wrap(err, "confirm delete")
+				}
+				if !ok {
+					return errors.New("forward cancelled")
+				}
+			}
+
+			reporter := iostreams.From(cmd.Context()).NewReporter()
+			taskID := fmt.Sprintf("%d->%d", fromID, toID)
+
+			err = tRun(cmd.Context(), func(ctx context.Context, c *telegram.Client, kvd kv.KV) error {
+				ids := make([]int, len(messages))
+				randIDs := make([]int64, len(messages))
+				for i, m := range messages {
+					ids[i] = m
+					randID, err := newRandomID()
+					if err != nil {
+						return errors.Wrap(err, "generate random id")
+					}
+					randIDs[i] = randID
+				}
+
+				if _, err := c.API().MessagesForwardMessages(ctx, &tg.MessagesForwardMessagesRequest{
+					FromPeer: &tg.InputPeerChat{ChatID: fromID},
+					ToPeer:   &tg.InputPeerChat{ChatID: toID},
+					ID:       ids,
+					RandomID: randIDs,
+				}); err != nil {
+					return errors.Wrap(err, "forward messages")
+				}
+				reporter.Progress(taskID, int64(len(ids)), int64(len(ids)), 0)
+
+				if deleteSrc {
+					if _, err := c.API().MessagesDeleteMessages(ctx, &tg.MessagesDeleteMessagesRequest{
+						ID:     ids,
+						Revoke: true,
+					}); err != nil {
+						return errors.Wrap(err, "delete source messages")
+					}
+				}
+
+				return nil
+			})
+			if err != nil {
+				reporter.Error(taskID, err)
+				return err
+			}
+
+			reporter.Done(taskID)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagForwardFrom, "", "source chat id")
+	cmd.Flags().String(flagForwardTo, "", "destination chat id")
+	cmd.Flags().IntSlice(flagForwardMessages, nil, "message ids to forward")
+	cmd.Flags().Bool(flagForwardDeleteSrc, false, "delete the source messages after forwarding")
+
+	_ = cmd.MarkFlagRequired(flagForwardFrom)
+	_ = cmd.MarkFlagRequired(flagForwardTo)
+	_ = cmd.MarkFlagRequired(flagForwardMessages)
+
+	return cmd
+}
+
+// newRandomID generates the random_id MTProto requires per forwarded
+// message, so retried/duplicate calls can be deduped server-side instead of
+// silently colliding with a previous run's ids.
+func newRandomID() (int64, error) {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint64(buf[:])), nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/go-faster/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/iyear/tdl/pkg/helpers"
+)
+
+// NewMigrate exposes migrateLegacyToBolt as an explicit command, for users
+// who skipped the automatic prompt in root.go (e.g. passed --storage
+// explicitly the first time, so the prompt never ran) and want to migrate
+// legacy storage into bolt later.
+func NewMigrate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "migrate",
+		Short:   "Migrate legacy storage to the bolt storage engine",
+		GroupID: groupAccount.ID,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ok, err := helpers.Confirm(cmd.Context(), "This will copy all namespaces from legacy storage into bolt storage, overwriting any existing bolt data. Continue?")
+			if err != nil {
+				return errors.Wrap(err, "confirm migration")
+			}
+			if !ok {
+				return errors.New("migration cancelled")
+			}
+
+			if err = migrateLegacyToBolt(); err != nil {
+				return errors.Wrap(err, "migrate legacy to bolt")
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "legacy storage migrated to bolt")
+			return nil
+		},
+	}
+
+	return cmd
+}
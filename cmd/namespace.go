@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-faster/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/iyear/tdl/pkg/helpers"
+	"github.com/iyear/tdl/pkg/kv"
+	"github.com/iyear/tdl/pkg/nsarchive"
+)
+
+const flagPassphrase = "passphrase"
+
+func NewNamespace() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "namespace",
+		Short:   "Manage namespaces (isolated Telegram sessions)",
+		GroupID: groupAccount.ID,
+	}
+
+	cmd.AddCommand(NewNamespaceList(), NewNamespaceCreate(), NewNamespaceDelete(),
+		NewNamespaceRename(), NewNamespaceExport(), NewNamespaceImport())
+
+	return cmd
+}
+
+func NewNamespaceList() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List all namespaces",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespaces, err := kv.From(cmd.Context()).Namespaces()
+			if err != nil {
+				return errors.Wrap(err, "list namespaces")
+			}
+
+			sort.Strings(namespaces)
+			for _, ns := range namespaces {
+				fmt.Fprintln(cmd.OutOrStdout(), ns)
+			}
+			return nil
+		},
+	}
+}
+
+func NewNamespaceCreate() *cobra.Command {
+	return &cobra.Command{
+		Use:   "create <namespace>",
+		Short: "Create an empty namespace",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Open creates the namespace if it doesn't already exist, same as
+			// every other command does implicitly via --namespace. This holds
+			// for the bolt and legacy drivers; the s3, gcs, redis and postgres
+			// drivers store nothing until a key is written, so they do not
+			// list an empty namespace.
+			if _, err := kv.From(cmd.Context()).Open(args[0]); err != nil {
+				return errors.Wrap(err, "create namespace")
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "namespace %q created\n", args[0])
+			return nil
+		},
+	}
+}
+
+func NewNamespaceDelete() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <namespace>",
+		Short: "Delete all of a namespace's contents",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ns := args[0]
+
+			ok, err := helpers.Confirm(cmd.Context(), fmt.Sprintf("This will permanently delete namespace %q. Continue?", ns))
+			if err != nil {
+				return errors.Wrap(err, "confirm delete")
+			}
+			if !ok {
+				return errors.New("delete cancelled")
+			}
+
+			if err = deleteNamespace(kv.From(cmd.Context()), ns); err != nil {
+				return errors.Wrap(err, "delete namespace")
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "namespace %q deleted\n", ns)
+			return nil
+		},
+	}
+}
+
+func NewNamespaceRename() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename a namespace",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			engine := kv.From(cmd.Context())
+
+			if err := nsarchive.Copy(engine, args[0], args[1]); err != nil {
+				return errors.Wrap(err, "copy namespace")
+			}
+			if err := deleteNamespace(engine, args[0]); err != nil {
+				return errors.Wrap(err, "delete old namespace")
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "namespace %q renamed to %q\n", args[0], args[1])
+			return nil
+		},
+	}
+}
+
+func NewNamespaceExport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <namespace> <file>",
+		Short: "Export a namespace to a portable, passphrase-encrypted archive",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			passphrase, _ := cmd.Flags().GetString(flagPassphrase)
+			if passphrase == "" {
+				return errors.New("--passphrase is required")
+			}
+
+			if err := nsarchive.Export(kv.From(cmd.Context()), args[0], args[1], passphrase); err != nil {
+				return errors.Wrap(err, "export namespace")
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "namespace %q exported to %s\n", args[0], args[1])
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagPassphrase, "", "passphrase used to encrypt the archive")
+	_ = cmd.MarkFlagRequired(flagPassphrase)
+
+	return cmd
+}
+
+func NewNamespaceImport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file> <namespace>",
+		Short: "Import a namespace from an archive created by 'tdl namespace export'",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			passphrase, _ := cmd.Flags().GetString(flagPassphrase)
+			if passphrase == "" {
+				return errors.New("--passphrase is required")
+			}
+
+			if err := nsarchive.Import(kv.From(cmd.Context()), args[0], args[1], passphrase); err != nil {
+				return errors.Wrap(err, "import namespace")
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "namespace %q imported from %s\n", args[1], args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagPassphrase, "", "passphrase used to decrypt the archive")
+	_ = cmd.MarkFlagRequired(flagPassphrase)
+
+	return cmd
+}
+
+// deleteNamespace removes every key in ns. There's no lower-level
+// "drop namespace" primitive, so we walk and delete key by key. The bolt
+// and legacy drivers keep the emptied namespace itself, so it is still
+// listed afterwards.
+func deleteNamespace(engine kv.KV, ns string) error {
+	h, err := engine.Open(ns)
+	if err != nil {
+		return errors.Wrap(err, "open namespace")
+	}
+
+	keys, err := h.Keys()
+	if err != nil {
+		return errors.Wrap(err, "list keys")
+	}
+
+	for _, key := range keys {
+		if err = h.Delete(key); err != nil {
+			return errors.Wrapf(err, "delete key %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/go-faster/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/iyear/tdl/pkg/helpers"
+	"github.com/iyear/tdl/pkg/kv"
+)
+
+func NewRecover() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "recover <file>",
+		Short:   "Restore a backup produced by 'tdl backup' into the current storage",
+		GroupID: groupAccount.ID,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+
+			ok, err := helpers.Confirm(cmd.Context(),
+				fmt.Sprintf("This will overwrite any namespaces in the current storage that also exist in %s. Continue?", path))
+			if err != nil {
+				return errors.Wrap(err, "confirm recover")
+			}
+			if !ok {
+				return errors.New("recover cancelled")
+			}
+
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				return errors.Wrap(err, "read backup file")
+			}
+
+			var meta kv.Meta
+			if err = json.Unmarshal(raw, &meta); err != nil {
+				return errors.Wrap(err, "unmarshal backup")
+			}
+
+			if err = kv.From(cmd.Context()).MigrateFrom(&meta); err != nil {
+				return errors.Wrap(err, "restore storage")
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "storage restored from %s\n", path)
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -19,11 +19,25 @@ import (
 	tclientcore "github.com/iyear/tdl/core/tclient"
 	"github.com/iyear/tdl/core/util/fsutil"
 	"github.com/iyear/tdl/core/util/logutil"
+	"github.com/iyear/tdl/pkg/config"
 	"github.com/iyear/tdl/pkg/consts"
+	"github.com/iyear/tdl/pkg/helpers"
+	"github.com/iyear/tdl/pkg/iostreams"
 	"github.com/iyear/tdl/pkg/kv"
 	"github.com/iyear/tdl/pkg/tclient"
 )
 
+// flags that aren't worth promoting to pkg/consts since they're only used
+// within package cmd, not by the rest of the tree.
+const (
+	flagYes              = "yes"
+	flagNoColor          = "no-color"
+	flagNamespaceRequire = "namespace-require"
+	flagConfig           = "config"
+	flagProfile          = "profile"
+	flagOutput           = "output"
+)
+
 var (
 	defaultBoltPath = filepath.Join(consts.DataDir, "data")
 
@@ -56,6 +70,29 @@ func New() *cobra.Command {
 		SilenceErrors: true,
 		SilenceUsage:  true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// load config file + profile, as defaults beneath flags/env
+			if err := loadConfigDefaults(cmd); err != nil {
+				return errors.Wrap(err, "load config")
+			}
+
+			// init streams, so every subcommand reads/writes through one place
+			// instead of fmt.Println/coloredcobra globals
+			streams := iostreams.System()
+			streams.AlwaysYes = viper.GetBool(flagYes)
+			if viper.GetBool(flagNoColor) {
+				streams.SetColorEnabled(false)
+			}
+
+			output := iostreams.OutputMode(viper.GetString(flagOutput))
+			switch output {
+			case iostreams.OutputText, iostreams.OutputJSON, iostreams.OutputNDJSON:
+				streams.Output = output
+			default:
+				return errors.Errorf("invalid --output %q, must be one of: text, json, ndjson", output)
+			}
+
+			cmd.SetContext(iostreams.With(cmd.Context(), streams))
+
 			// init logger
 			debug, level := viper.GetBool(consts.FlagDebug), zap.InfoLevel
 			if debug {
@@ -72,7 +109,15 @@ func New() *cobra.Command {
 
 			// v0.14.0: default storage changed from legacy to bolt, so we need to auto migrate to keep compatibility
 			if !cmd.Flags().Lookup(consts.FlagStorage).Changed && !fsutil.PathExists(defaultBoltPath) {
-				if err := migrateLegacyToBolt(); err != nil {
+				ok, err := helpers.Confirm(cmd.Context(), "No existing storage found, tdl will migrate your legacy data to the new bolt storage. Continue?")
+				if err != nil {
+					return errors.Wrap(err, "confirm storage migration")
+				}
+				if !ok {
+					return errors.New("storage migration declined, pass --storage to pick an existing storage explicitly")
+				}
+
+				if err = migrateLegacyToBolt(); err != nil {
 					return errors.Wrap(err, "migrate legacy to bolt")
 				}
 			}
@@ -111,7 +156,8 @@ func New() *cobra.Command {
 	cmd.AddGroup(groupAccount, groupTools)
 
 	cmd.AddCommand(NewVersion(), NewLogin(), NewDownload(), NewForward(),
-		NewChat(), NewUpload(), NewBackup(), NewRecover(), NewMigrate(), NewGen())
+		NewChat(), NewUpload(), NewBackup(), NewRecover(), NewMigrate(), NewGen(),
+		NewStorage(), NewNamespace(), NewConfig())
 
 	cmd.PersistentFlags().StringToString(consts.FlagStorage,
 		DefaultBoltStorage,
@@ -122,6 +168,16 @@ func New() *cobra.Command {
 	cmd.PersistentFlags().StringP(consts.FlagNamespace, "n", "default", "namespace for Telegram session")
 	cmd.PersistentFlags().Bool(consts.FlagDebug, false, "enable debug mode")
 
+	cmd.PersistentFlags().BoolP(flagYes, "y", false, "auto-confirm any destructive or interactive prompts")
+	cmd.PersistentFlags().Bool(flagNoColor, false, "disable colored output, also honors the NO_COLOR env var")
+	cmd.PersistentFlags().Bool(flagNamespaceRequire, false, "fail instead of auto-creating the namespace if it doesn't already exist")
+
+	cmd.PersistentFlags().String(flagConfig, "", "config file (default: $XDG_CONFIG_HOME/tdl/config.yaml)")
+	cmd.PersistentFlags().String(flagProfile, "", "config profile to use, see 'tdl config profiles'")
+
+	cmd.PersistentFlags().String(flagOutput, string(iostreams.OutputText),
+		"output mode: text (human progress), json, or ndjson (one JSON event per line)")
+
 	cmd.PersistentFlags().IntP(consts.FlagPartSize, "s", 512*1024, "part size for transfer")
 	cmd.PersistentFlags().IntP(consts.FlagThreads, "t", 4, "max threads for transfer one item")
 	cmd.PersistentFlags().IntP(consts.FlagLimit, "l", 2, "max number of concurrent tasks")
@@ -167,9 +223,61 @@ func completeExtFiles(ext ...string) completeFunc {
 	}
 }
 
+// loadConfigDefaults reads the config file (if any) and installs the active
+// profile's values as viper defaults, so the precedence ends up flag > env >
+// profile > file-defaults > built-in default: flags/env already outrank
+// viper defaults, and we resolve profile-over-file-defaults ourselves before
+// handing viper a single merged value per key.
+func loadConfigDefaults(cmd *cobra.Command) error {
+	path, _ := cmd.Flags().GetString(flagConfig)
+	if path == "" {
+		p, err := config.Path()
+		if err != nil {
+			return errors.Wrap(err, "resolve default config path")
+		}
+		path = p
+	}
+
+	file, err := config.Load(path)
+	if err != nil {
+		return errors.Wrapf(err, "load config file %s", path)
+	}
+
+	profile := viper.GetString(flagProfile)
+	for key, value := range config.Resolve(file, profile) {
+		viper.SetDefault(key, value)
+	}
+	return nil
+}
+
+// requireNamespace returns an error if ns doesn't already exist, for
+// --namespace-require/TDL_NAMESPACE_REQUIRE so typos in -n don't silently
+// spin up a brand new, empty session.
+func requireNamespace(ctx context.Context, ns string) error {
+	namespaces, err := kv.From(ctx).Namespaces()
+	if err != nil {
+		return errors.Wrap(err, "list namespaces")
+	}
+
+	for _, n := range namespaces {
+		if n == ns {
+			return nil
+		}
+	}
+	return errors.Errorf("namespace %q does not exist, run 'tdl namespace create %s' first or drop --namespace-require", ns, ns)
+}
+
 func tRun(ctx context.Context, f func(ctx context.Context, c *telegram.Client, kvd kv.KV) error, middlewares ...telegram.Middleware) error {
+	ns := viper.GetString(consts.FlagNamespace)
+
+	if viper.GetBool(flagNamespaceRequire) {
+		if err := requireNamespace(ctx, ns); err != nil {
+			return err
+		}
+	}
+
 	// init tclient kv
-	kvd, err := kv.From(ctx).Open(viper.GetString(consts.FlagNamespace))
+	kvd, err := kv.From(ctx).Open(ns)
 	if err != nil {
 		return errors.Wrap(err, "open kv storage")
 	}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-faster/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/iyear/tdl/core/logctx"
+	"github.com/iyear/tdl/pkg/helpers"
+	"github.com/iyear/tdl/pkg/iostreams"
+	"github.com/iyear/tdl/pkg/kv"
+)
+
+const (
+	flagStorageFrom   = "from"
+	flagStorageTo     = "to"
+	flagStorageDryRun = "dry-run"
+)
+
+// storageSecretKeys lists the --storage/--from/--to option keys that may
+// carry credentials (redis uses password, postgres uses dsn; secret/token
+// are redacted too, although the s3 and gcs drivers read no credential
+// options and take credentials from their SDK's default chain). Redacted
+// before any of these maps are echoed back to the user, e.g. in
+// confirmation prompts.
+var storageSecretKeys = map[string]bool{
+	"password": true,
+	"dsn":      true,
+	"secret":   true,
+	"token":    true,
+}
+
+// redactStorageOptions returns a copy of options with secret-bearing values
+// replaced by a fixed placeholder, safe to interpolate into terminal output.
+func redactStorageOptions(options map[string]string) map[string]string {
+	redacted := make(map[string]string, len(options))
+	for k, v := range options {
+		if storageSecretKeys[k] {
+			v = "***"
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+func NewStorage() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "storage",
+		Short:   "Manage tdl's KV storage engines",
+		GroupID: groupTools.ID,
+	}
+
+	cmd.AddCommand(NewStorageMigrate())
+
+	return cmd
+}
+
+func NewStorageMigrate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate all namespaces from one storage engine to another",
+		Long: "Migrate streams every namespace from the source engine to the destination engine, " +
+			"one namespace at a time. A namespace that fails is reported and the remaining namespaces are " +
+			"still migrated; a re-run migrates every namespace again, not only the ones that failed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, _ := cmd.Flags().GetStringToString(flagStorageFrom)
+			to, _ := cmd.Flags().GetStringToString(flagStorageTo)
+			dryRun, _ := cmd.Flags().GetBool(flagStorageDryRun)
+
+			return storageMigrate(cmd, from, to, dryRun)
+		},
+	}
+
+	cmd.Flags().StringToString(flagStorageFrom, nil, "source storage options, format: type=driver,key1=value1,key2=value2")
+	cmd.Flags().StringToString(flagStorageTo, nil, "destination storage options, format: type=driver,key1=value1,key2=value2")
+	cmd.Flags().Bool(flagStorageDryRun, false, "list the namespaces that would be migrated without writing anything")
+
+	_ = cmd.MarkFlagRequired(flagStorageFrom)
+	_ = cmd.MarkFlagRequired(flagStorageTo)
+
+	return cmd
+}
+
+func storageMigrate(cmd *cobra.Command, from, to map[string]string, dryRun bool) (rerr error) {
+	log := logctx.From(cmd.Context())
+	streams := iostreams.From(cmd.Context())
+	reporter := streams.NewReporter()
+
+	if !dryRun {
+		ok, err := helpers.Confirm(cmd.Context(), fmt.Sprintf("This will overwrite any existing data in destination storage %v. Continue?", redactStorageOptions(to)))
+		if err != nil {
+			return errors.Wrap(err, "confirm migration")
+		}
+		if !ok {
+			return errors.New("migration cancelled")
+		}
+	}
+
+	src, err := kv.NewWithMap(from)
+	if err != nil {
+		return errors.Wrap(err, "open source storage")
+	}
+	defer multierr.AppendInvoke(&rerr, multierr.Close(src))
+
+	dst, err := kv.NewWithMap(to)
+	if err != nil {
+		return errors.Wrap(err, "open destination storage")
+	}
+	defer multierr.AppendInvoke(&rerr, multierr.Close(dst))
+
+	namespaces, err := src.Namespaces()
+	if err != nil {
+		return errors.Wrap(err, "list source namespaces")
+	}
+	sort.Strings(namespaces)
+
+	fmt.Fprintf(streams.Out, "found %d namespace(s) to migrate\n", len(namespaces))
+
+	var failed []string
+	for _, ns := range namespaces {
+		if dryRun {
+			fmt.Fprintf(streams.Out, "would migrate namespace %q\n", ns)
+			continue
+		}
+
+		// migrateNamespace streams this namespace's keys straight from src to
+		// dst, so a transient read failure only costs this one namespace's
+		// progress and the loop goes on with the rest. A re-run copies every
+		// namespace again, not only those in `failed`.
+		n, err := migrateNamespace(src, dst, ns)
+		if err != nil {
+			log.Error("migrate namespace failed, resuming with remaining namespaces",
+				zap.String("namespace", ns), zap.Error(err))
+			failed = append(failed, ns)
+			reporter.Error(ns, err)
+			continue
+		}
+
+		reporter.Progress(ns, int64(n), int64(n), 0)
+		reporter.Done(ns)
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("failed to migrate namespace(s), re-run to retry: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// migrateNamespace copies namespace's keys one at a time from src to dst,
+// reading and writing a single key at a time rather than buffering the
+// whole namespace in memory. Returns the number of value bytes copied.
+func migrateNamespace(src, dst kv.KV, namespace string) (int, error) {
+	from, err := src.Open(namespace)
+	if err != nil {
+		return 0, errors.Wrap(err, "open source namespace")
+	}
+
+	keys, err := from.Keys()
+	if err != nil {
+		return 0, errors.Wrap(err, "list keys")
+	}
+
+	to, err := dst.Open(namespace)
+	if err != nil {
+		return 0, errors.Wrap(err, "open destination namespace")
+	}
+
+	n := 0
+	for _, key := range keys {
+		value, err := from.Get(key)
+		if err != nil {
+			return 0, errors.Wrapf(err, "get key %q", key)
+		}
+		if err = to.Set(key, value); err != nil {
+			return 0, errors.Wrapf(err, "set key %q", key)
+		}
+		n += len(value)
+	}
+	return n, nil
+}
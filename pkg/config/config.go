@@ -0,0 +1,138 @@
+// Package config implements tdl's on-disk config file: named profiles that
+// supply defaults for flags like --storage, --proxy or --threads, so users
+// juggling multiple accounts/DCs/proxies don't have to retype them.
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/go-faster/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Path returns the default config file location, honoring XDG_CONFIG_HOME.
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.Wrap(err, "resolve user config dir")
+	}
+	return filepath.Join(dir, "tdl", "config.yaml"), nil
+}
+
+// Profile holds the per-profile values that can be layered under flags/env.
+// Every field is optional: an unset field simply isn't applied, falling
+// through to the next lower-precedence source.
+type Profile struct {
+	Storage  map[string]string `yaml:"storage,omitempty"`
+	Proxy    string            `yaml:"proxy,omitempty"`
+	NTP      string            `yaml:"ntp,omitempty"`
+	PartSize int               `yaml:"part-size,omitempty"`
+	Threads  int               `yaml:"threads,omitempty"`
+	Limit    int               `yaml:"limit,omitempty"`
+	// PoolSize is a pointer because 0 is a meaningful, explicit value
+	// ("zero means infinity", see --pool-size's help text) rather than
+	// "unset" like the other int fields here.
+	PoolSize *int `yaml:"pool-size,omitempty"`
+}
+
+// File is the config.yaml schema. Defaults applies to every profile unless
+// overridden by the profile itself.
+type File struct {
+	Defaults Profile            `yaml:"defaults,omitempty"`
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+}
+
+// Load reads and parses the config file at path. A missing file is not an
+// error: it's treated as an empty File, since config files are optional.
+func Load(path string) (*File, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "read config file")
+	}
+
+	var f File
+	if err = yaml.Unmarshal(raw, &f); err != nil {
+		return nil, errors.Wrap(err, "parse config file")
+	}
+	return &f, nil
+}
+
+// Save writes f back to path, creating parent directories as needed.
+func Save(path string, f *File) error {
+	raw, err := yaml.Marshal(f)
+	if err != nil {
+		return errors.Wrap(err, "marshal config file")
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.Wrap(err, "create config dir")
+	}
+	return errors.Wrap(os.WriteFile(path, raw, 0o600), "write config file")
+}
+
+// Resolve merges the Defaults profile with the named profile (profile wins
+// on conflicts) into a flag-name -> value map, ready to be installed as
+// viper defaults. An empty profile name resolves to just the Defaults.
+func Resolve(f *File, profile string) map[string]interface{} {
+	merged := f.Defaults
+	if profile != "" {
+		if p, ok := f.Profiles[profile]; ok {
+			merged = mergeProfile(merged, p)
+		}
+	}
+
+	values := map[string]interface{}{}
+	if merged.Storage != nil {
+		values["storage"] = merged.Storage
+	}
+	if merged.Proxy != "" {
+		values["proxy"] = merged.Proxy
+	}
+	if merged.NTP != "" {
+		values["ntp"] = merged.NTP
+	}
+	if merged.PartSize != 0 {
+		values["part-size"] = merged.PartSize
+	}
+	if merged.Threads != 0 {
+		values["threads"] = merged.Threads
+	}
+	if merged.Limit != 0 {
+		values["limit"] = merged.Limit
+	}
+	if merged.PoolSize != nil {
+		values["pool-size"] = *merged.PoolSize
+	}
+	return values
+}
+
+// mergeProfile overlays override's non-zero fields onto base.
+func mergeProfile(base, override Profile) Profile {
+	merged := base
+	if override.Storage != nil {
+		merged.Storage = override.Storage
+	}
+	if override.Proxy != "" {
+		merged.Proxy = override.Proxy
+	}
+	if override.NTP != "" {
+		merged.NTP = override.NTP
+	}
+	if override.PartSize != 0 {
+		merged.PartSize = override.PartSize
+	}
+	if override.Threads != 0 {
+		merged.Threads = override.Threads
+	}
+	if override.Limit != 0 {
+		merged.Limit = override.Limit
+	}
+	if override.PoolSize != nil {
+		merged.PoolSize = override.PoolSize
+	}
+	return merged
+}
@@ -0,0 +1,53 @@
+package config
+
+import "testing"
+
+func TestResolve(t *testing.T) {
+	f := &File{
+		Defaults: Profile{Proxy: "socks5://default", Threads: 4},
+		Profiles: map[string]Profile{
+			"prod": {Threads: 8},
+		},
+	}
+
+	got := Resolve(f, "prod")
+	if got["proxy"] != "socks5://default" {
+		t.Errorf("proxy = %v, want default to fall through", got["proxy"])
+	}
+	if got["threads"] != 8 {
+		t.Errorf("threads = %v, want profile override 8", got["threads"])
+	}
+
+	got = Resolve(f, "")
+	if got["threads"] != 4 {
+		t.Errorf("threads = %v, want default 4 with no profile selected", got["threads"])
+	}
+
+	got = Resolve(f, "missing")
+	if got["threads"] != 4 {
+		t.Errorf("threads = %v, want default 4 for an unknown profile", got["threads"])
+	}
+}
+
+func TestResolvePoolSizeZero(t *testing.T) {
+	zero := 0
+	f := &File{
+		Profiles: map[string]Profile{
+			"unlimited": {PoolSize: &zero},
+		},
+	}
+
+	got := Resolve(f, "unlimited")
+	size, ok := got["pool-size"]
+	if !ok {
+		t.Fatalf("pool-size missing from resolved config, want explicit 0 to survive")
+	}
+	if size != 0 {
+		t.Errorf("pool-size = %v, want 0", size)
+	}
+
+	got = Resolve(f, "missing")
+	if _, ok := got["pool-size"]; ok {
+		t.Errorf("pool-size = %v, want absent when never set", got["pool-size"])
+	}
+}
@@ -0,0 +1,43 @@
+// Package helpers holds small command-facing utilities shared across
+// tdl's cobra subcommands.
+package helpers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/iyear/tdl/pkg/iostreams"
+)
+
+// Confirm prompts the user with msg and reads a y/n answer from the
+// streams attached to ctx. It auto-accepts when --yes was passed, and
+// otherwise refuses (rather than hanging or silently proceeding) when stdin
+// isn't a TTY, so piped/scripted invocations fail loudly instead of
+// blocking forever or running a destructive command unattended. Returns
+// false on anything other than an explicit "y"/"yes".
+func Confirm(ctx context.Context, msg string) (bool, error) {
+	streams := iostreams.From(ctx)
+
+	if streams.AlwaysYes {
+		return true, nil
+	}
+	if !streams.IsInputTTY() {
+		return false, fmt.Errorf("%s: refusing to prompt on a non-interactive terminal, pass --yes to confirm", msg)
+	}
+
+	fmt.Fprintf(streams.Out, "%s [y/N]: ", msg)
+
+	line, err := bufio.NewReader(streams.In).ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
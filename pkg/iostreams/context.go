@@ -0,0 +1,21 @@
+package iostreams
+
+import "context"
+
+type ctxKey struct{}
+
+// With attaches streams to ctx, following the same pattern as logctx/kv.With
+// so commands can fetch it back out via From without threading it through
+// every function signature.
+func With(ctx context.Context, streams *IOStreams) context.Context {
+	return context.WithValue(ctx, ctxKey{}, streams)
+}
+
+// From returns the streams attached to ctx, or System() if none were set.
+func From(ctx context.Context) *IOStreams {
+	streams, ok := ctx.Value(ctxKey{}).(*IOStreams)
+	if !ok {
+		return System()
+	}
+	return streams
+}
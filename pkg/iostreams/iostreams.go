@@ -0,0 +1,76 @@
+// Package iostreams centralizes tdl's stdin/stdout/stderr handling so
+// commands don't reach for fmt.Println or package-level color globals
+// directly, and so behavior like color and TTY detection is consistent
+// and testable.
+package iostreams
+
+import (
+	"io"
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// OutputMode selects how commands render progress and results: a pretty
+// TTY-oriented view, or a machine-readable event stream.
+type OutputMode string
+
+const (
+	OutputText   OutputMode = "text"
+	OutputJSON   OutputMode = "json"
+	OutputNDJSON OutputMode = "ndjson"
+)
+
+// IOStreams bundles the input/output handles a command needs, plus the
+// terminal capabilities derived from them.
+type IOStreams struct {
+	In     io.Reader
+	Out    io.Writer
+	ErrOut io.Writer
+
+	colorEnabled bool
+	isTTY        bool
+	isInputTTY   bool
+
+	// AlwaysYes skips interactive confirmations, set by the root --yes flag.
+	AlwaysYes bool
+
+	// Output selects the rendering mode for progress/results, set by the
+	// root --output flag. Defaults to OutputText.
+	Output OutputMode
+}
+
+// System builds the IOStreams for a real terminal session, detecting color
+// and TTY support from the process's standard file descriptors.
+func System() *IOStreams {
+	noColor := os.Getenv("NO_COLOR") != ""
+	isTTY := isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsTerminal(os.Stderr.Fd())
+
+	return &IOStreams{
+		In:           os.Stdin,
+		Out:          os.Stdout,
+		ErrOut:       os.Stderr,
+		colorEnabled: isTTY && !noColor,
+		isTTY:        isTTY,
+		isInputTTY:   isatty.IsTerminal(os.Stdin.Fd()),
+		Output:       OutputText,
+	}
+}
+
+// Test builds an IOStreams over the given buffers, for unit tests that
+// exercise commands without touching the real terminal.
+func Test(in io.Reader, out, errOut io.Writer) *IOStreams {
+	return &IOStreams{In: in, Out: out, ErrOut: errOut, Output: OutputText}
+}
+
+func (s *IOStreams) ColorEnabled() bool { return s.colorEnabled }
+
+func (s *IOStreams) IsTTY() bool { return s.isTTY }
+
+// IsInputTTY reports whether stdin is an interactive terminal, as opposed to
+// IsTTY (stdout/stderr) — the stream that matters when deciding whether it's
+// safe to block reading an interactive confirmation from the user.
+func (s *IOStreams) IsInputTTY() bool { return s.isInputTTY }
+
+// SetColorEnabled overrides color detection, e.g. for --no-color.
+func (s *IOStreams) SetColorEnabled(enabled bool) { s.colorEnabled = enabled }
@@ -0,0 +1,86 @@
+package iostreams
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ProgressEvent is one line of NDJSON output emitted while OutputMode is
+// json/ndjson. The byte and speed fields are set (even when zero) only on
+// "progress" events; Error is set only on the final "error" event for a task.
+type ProgressEvent struct {
+	TS         int64  `json:"ts"`
+	Event      string `json:"event"` // "progress" | "done" | "error"
+	TaskID     string `json:"task_id"`
+	BytesDone  *int64 `json:"bytes_done,omitempty"`
+	BytesTotal *int64 `json:"bytes_total,omitempty"`
+	SpeedBps   *int64 `json:"speed_bps,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Reporter is how a long-running command (download/upload/forward/backup)
+// surfaces progress, regardless of the active OutputMode.
+type Reporter interface {
+	// Progress reports bytesDone/bytesTotal/speedBps for taskID.
+	Progress(taskID string, bytesDone, bytesTotal, speedBps int64)
+	// Done marks taskID finished successfully.
+	Done(taskID string)
+	// Error marks taskID finished with err.
+	Error(taskID string, err error)
+}
+
+// NewReporter returns the Reporter matching s.Output: a human progress line
+// for OutputText, or one NDJSON object per event for OutputJSON/OutputNDJSON.
+func (s *IOStreams) NewReporter() Reporter {
+	if s.Output == OutputText {
+		return &textReporter{out: s.Out}
+	}
+	return &ndjsonReporter{out: s.Out}
+}
+
+type textReporter struct {
+	out io.Writer
+}
+
+func (r *textReporter) Progress(taskID string, bytesDone, bytesTotal, speedBps int64) {
+	fmt.Fprintf(r.out, "%s: %d/%d bytes (%d B/s)\n", taskID, bytesDone, bytesTotal, speedBps)
+}
+
+func (r *textReporter) Done(taskID string) {
+	fmt.Fprintf(r.out, "%s: done\n", taskID)
+}
+
+func (r *textReporter) Error(taskID string, err error) {
+	fmt.Fprintf(r.out, "%s: error: %v\n", taskID, err)
+}
+
+// ndjsonReporter emits one JSON object per line, documented on ProgressEvent:
+// {"ts","event":"progress","task_id","bytes_done","bytes_total","speed_bps"}
+// and terminal events {"event":"done"|"error","task_id","error"?}.
+type ndjsonReporter struct {
+	out io.Writer
+}
+
+func (r *ndjsonReporter) emit(e ProgressEvent) {
+	e.TS = time.Now().Unix()
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	raw = append(raw, '\n')
+	_, _ = r.out.Write(raw)
+}
+
+func (r *ndjsonReporter) Progress(taskID string, bytesDone, bytesTotal, speedBps int64) {
+	r.emit(ProgressEvent{Event: "progress", TaskID: taskID, BytesDone: &bytesDone, BytesTotal: &bytesTotal, SpeedBps: &speedBps})
+}
+
+func (r *ndjsonReporter) Done(taskID string) {
+	r.emit(ProgressEvent{Event: "done", TaskID: taskID})
+}
+
+func (r *ndjsonReporter) Error(taskID string, err error) {
+	r.emit(ProgressEvent{Event: "error", TaskID: taskID, Error: err.Error()})
+}
@@ -0,0 +1,120 @@
+package kv
+
+import (
+	"time"
+
+	"github.com/go-faster/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// DriverBolt is the default storage engine: a single boltdb file, one bucket
+// per namespace. No network dependency, safe for a single local process.
+const DriverBolt DriverType = "bolt"
+
+func init() {
+	Register(DriverBolt, newBoltKV)
+}
+
+type boltKV struct {
+	db        *bolt.DB
+	namespace string
+}
+
+// newBoltKV builds a KV engine backed by a local boltdb file. Required
+// option: path.
+func newBoltKV(options map[string]string) (KV, error) {
+	path, ok := options["path"]
+	if !ok || path == "" {
+		return nil, errors.New("bolt storage requires a 'path' option")
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, errors.Wrap(err, "open bolt db")
+	}
+
+	return &boltKV{db: db}, nil
+}
+
+func (k *boltKV) Open(namespace string) (KV, error) {
+	err := k.db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(namespace))
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "create bucket %q", namespace)
+	}
+
+	return &boltKV{db: k.db, namespace: namespace}, nil
+}
+
+func (k *boltKV) Namespaces() ([]string, error) {
+	var ns []string
+	err := k.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			ns = append(ns, string(name))
+			return nil
+		})
+	})
+	return ns, errors.Wrap(err, "list buckets")
+}
+
+func (k *boltKV) Keys() ([][]byte, error) {
+	var keys [][]byte
+	err := k.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(k.namespace))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(key, _ []byte) error {
+			keys = append(keys, append([]byte(nil), key...))
+			return nil
+		})
+	})
+	return keys, errors.Wrap(err, "iterate bucket")
+}
+
+func (k *boltKV) Get(key []byte) ([]byte, error) {
+	var value []byte
+	err := k.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(k.namespace))
+		if b == nil {
+			return nil
+		}
+		value = append([]byte(nil), b.Get(key)...)
+		return nil
+	})
+	return value, errors.Wrap(err, "get key")
+}
+
+func (k *boltKV) Set(key, value []byte) error {
+	return errors.Wrap(k.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(k.namespace))
+		if err != nil {
+			return err
+		}
+		return b.Put(key, value)
+	}), "set key")
+}
+
+func (k *boltKV) Delete(key []byte) error {
+	return errors.Wrap(k.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(k.namespace))
+		if b == nil {
+			return nil
+		}
+		return b.Delete(key)
+	}), "delete key")
+}
+
+func (k *boltKV) MigrateTo() (*Meta, error) {
+	return exportMeta(k)
+}
+
+func (k *boltKV) MigrateFrom(meta *Meta) error {
+	return importMeta(k, meta)
+}
+
+func (k *boltKV) Close() error {
+	return k.db.Close()
+}
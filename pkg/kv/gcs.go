@@ -0,0 +1,154 @@
+package kv
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/go-faster/errors"
+	"google.golang.org/api/iterator"
+)
+
+// DriverGCS stores each namespace as a tree of objects under a GCS bucket,
+// the same layout as DriverS3.
+const DriverGCS DriverType = "gcs"
+
+func init() {
+	Register(DriverGCS, newGCSKV)
+}
+
+type gcsKV struct {
+	client    *storage.Client
+	bucket    string
+	prefix    string
+	namespace string
+}
+
+// newGCSKV builds a KV engine backed by a Google Cloud Storage bucket.
+// Required option: bucket. Optional: prefix. Credentials are resolved the
+// usual way (GOOGLE_APPLICATION_CREDENTIALS, metadata server, etc.).
+func newGCSKV(options map[string]string) (KV, error) {
+	bucket, ok := options["bucket"]
+	if !ok || bucket == "" {
+		return nil, errors.New("gcs storage requires a 'bucket' option")
+	}
+
+	client, err := storage.NewClient(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(err, "create gcs client")
+	}
+
+	return &gcsKV{client: client, bucket: bucket, prefix: options["prefix"]}, nil
+}
+
+// rootPrefix returns the configured prefix with a trailing separator, or ""
+// when no prefix is set. Building prefixes this way (instead of path.Join,
+// which collapses "" away to "." and strips trailing separators) keeps the
+// "/" boundary between prefix and namespace explicit and never produces a
+// bare "/" when prefix is unset.
+func (k *gcsKV) rootPrefix() string {
+	if k.prefix == "" {
+		return ""
+	}
+	return k.prefix + "/"
+}
+
+func (k *gcsKV) namespacePrefix() string {
+	return k.rootPrefix() + k.namespace + "/"
+}
+
+func (k *gcsKV) objectKey(key string) string {
+	return k.namespacePrefix() + key
+}
+
+func (k *gcsKV) bucketHandle() *storage.BucketHandle {
+	return k.client.Bucket(k.bucket)
+}
+
+func (k *gcsKV) Open(namespace string) (KV, error) {
+	ns := *k
+	ns.namespace = namespace
+	return &ns, nil
+}
+
+func (k *gcsKV) Namespaces() ([]string, error) {
+	seen := map[string]struct{}{}
+	var ns []string
+
+	root := k.rootPrefix()
+	it := k.bucketHandle().Objects(context.Background(), &storage.Query{
+		Prefix:    root,
+		Delimiter: "",
+	})
+	for {
+		obj, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "list objects")
+		}
+		rel := strings.TrimPrefix(obj.Name, root)
+		if i := strings.IndexByte(rel, '/'); i >= 0 {
+			name := rel[:i]
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				ns = append(ns, name)
+			}
+		}
+	}
+	return ns, nil
+}
+
+func (k *gcsKV) Keys() ([][]byte, error) {
+	var keys [][]byte
+	prefix := k.namespacePrefix()
+
+	it := k.bucketHandle().Objects(context.Background(), &storage.Query{Prefix: prefix})
+	for {
+		obj, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "list objects")
+		}
+		keys = append(keys, []byte(strings.TrimPrefix(obj.Name, prefix)))
+	}
+	return keys, nil
+}
+
+func (k *gcsKV) Get(key []byte) ([]byte, error) {
+	r, err := k.bucketHandle().Object(k.objectKey(string(key))).NewReader(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(err, "open object")
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func (k *gcsKV) Set(key, value []byte) error {
+	w := k.bucketHandle().Object(k.objectKey(string(key))).NewWriter(context.Background())
+	if _, err := w.Write(value); err != nil {
+		_ = w.Close()
+		return errors.Wrap(err, "write object")
+	}
+	return errors.Wrap(w.Close(), "close object writer")
+}
+
+func (k *gcsKV) Delete(key []byte) error {
+	return errors.Wrap(k.bucketHandle().Object(k.objectKey(string(key))).Delete(context.Background()), "delete object")
+}
+
+func (k *gcsKV) MigrateTo() (*Meta, error) {
+	return exportMeta(k)
+}
+
+func (k *gcsKV) MigrateFrom(meta *Meta) error {
+	return importMeta(k, meta)
+}
+
+func (k *gcsKV) Close() error {
+	return k.client.Close()
+}
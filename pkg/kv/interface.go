@@ -0,0 +1,19 @@
+package kv
+
+// KV is the storage contract every driver (and every namespace handle
+// returned by Open) implements. Keys is part of the contract — not an
+// optional capability drivers duck-type their way into — since namespace
+// lifecycle commands (delete/rename/export) and MigrateTo/MigrateFrom all
+// need to enumerate a namespace's keys against any backend, bolt/legacy
+// included.
+type KV interface {
+	Open(namespace string) (KV, error)
+	Namespaces() ([]string, error)
+	Keys() ([][]byte, error)
+	Get(key []byte) ([]byte, error)
+	Set(key, value []byte) error
+	Delete(key []byte) error
+	MigrateTo() (*Meta, error)
+	MigrateFrom(meta *Meta) error
+	Close() error
+}
@@ -0,0 +1,62 @@
+package kv
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-faster/errors"
+)
+
+// DriverType identifies a registered KV backend, e.g. "bolt" or "s3".
+type DriverType string
+
+func (d DriverType) String() string { return string(d) }
+
+// DriverTypeKey is the --storage option key that selects the driver, e.g.
+// `--storage type=s3,bucket=...`.
+const DriverTypeKey = "type"
+
+// NewWithMap builds a KV engine from a --storage-style option map. The
+// DriverTypeKey entry selects the registered driver; the rest are passed
+// through to its Factory verbatim.
+func NewWithMap(options map[string]string) (KV, error) {
+	typ, ok := options[DriverTypeKey]
+	if !ok || typ == "" {
+		return nil, errors.Errorf("storage options must include %q", DriverTypeKey)
+	}
+	return newRegistered(DriverType(typ), options)
+}
+
+// DriverNames returns the registered driver types, sorted, for use in flag
+// help text.
+func DriverNames() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	names := make([]string, 0, len(drivers))
+	for typ := range drivers {
+		names = append(names, string(typ))
+	}
+	sort.Strings(names)
+	return names
+}
+
+type ctxKey struct{}
+
+// With attaches engine to ctx, following the same pattern as
+// logctx/iostreams.With so commands can fetch it back out via From without
+// threading it through every function signature.
+func With(ctx context.Context, engine KV) context.Context {
+	return context.WithValue(ctx, ctxKey{}, engine)
+}
+
+// From returns the KV engine attached to ctx. Panics if none was attached,
+// since every command path runs through root.go's PersistentPreRunE, which
+// always sets one.
+func From(ctx context.Context) KV {
+	engine, ok := ctx.Value(ctxKey{}).(KV)
+	if !ok {
+		panic("kv: no engine attached to context")
+	}
+	return engine
+}
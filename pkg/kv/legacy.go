@@ -0,0 +1,129 @@
+package kv
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/go-faster/errors"
+)
+
+// DriverLegacy is tdl's pre-v0.14.0 storage format: every namespace's keys
+// and values kept in one flat JSON file. Superseded by DriverBolt, kept
+// around only so existing installs can be auto-migrated on first run (see
+// migrateLegacyToBolt in cmd/root.go).
+const DriverLegacy DriverType = "legacy"
+
+func init() {
+	Register(DriverLegacy, newLegacyKV)
+}
+
+type legacyFile struct {
+	mu   sync.Mutex
+	path string
+	data map[string]map[string][]byte
+}
+
+type legacyKV struct {
+	file      *legacyFile
+	namespace string
+}
+
+// newLegacyKV builds a KV engine backed by a single flat JSON file. Required
+// option: path.
+func newLegacyKV(options map[string]string) (KV, error) {
+	path, ok := options["path"]
+	if !ok || path == "" {
+		return nil, errors.New("legacy storage requires a 'path' option")
+	}
+
+	data := map[string]map[string][]byte{}
+	if raw, err := os.ReadFile(path); err == nil {
+		if err = json.Unmarshal(raw, &data); err != nil {
+			return nil, errors.Wrap(err, "unmarshal legacy storage file")
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "read legacy storage file")
+	}
+
+	return &legacyKV{file: &legacyFile{path: path, data: data}}, nil
+}
+
+func (f *legacyFile) flush() error {
+	raw, err := json.Marshal(f.data)
+	if err != nil {
+		return errors.Wrap(err, "marshal legacy storage file")
+	}
+	return errors.Wrap(os.WriteFile(f.path, raw, 0o600), "write legacy storage file")
+}
+
+func (k *legacyKV) Open(namespace string) (KV, error) {
+	k.file.mu.Lock()
+	defer k.file.mu.Unlock()
+
+	if _, ok := k.file.data[namespace]; !ok {
+		k.file.data[namespace] = map[string][]byte{}
+	}
+	return &legacyKV{file: k.file, namespace: namespace}, nil
+}
+
+func (k *legacyKV) Namespaces() ([]string, error) {
+	k.file.mu.Lock()
+	defer k.file.mu.Unlock()
+
+	ns := make([]string, 0, len(k.file.data))
+	for name := range k.file.data {
+		ns = append(ns, name)
+	}
+	return ns, nil
+}
+
+func (k *legacyKV) Keys() ([][]byte, error) {
+	k.file.mu.Lock()
+	defer k.file.mu.Unlock()
+
+	kvs := k.file.data[k.namespace]
+	keys := make([][]byte, 0, len(kvs))
+	for key := range kvs {
+		keys = append(keys, []byte(key))
+	}
+	return keys, nil
+}
+
+func (k *legacyKV) Get(key []byte) ([]byte, error) {
+	k.file.mu.Lock()
+	defer k.file.mu.Unlock()
+
+	return k.file.data[k.namespace][string(key)], nil
+}
+
+func (k *legacyKV) Set(key, value []byte) error {
+	k.file.mu.Lock()
+	if _, ok := k.file.data[k.namespace]; !ok {
+		k.file.data[k.namespace] = map[string][]byte{}
+	}
+	k.file.data[k.namespace][string(key)] = value
+	err := k.file.flush()
+	k.file.mu.Unlock()
+	return err
+}
+
+func (k *legacyKV) Delete(key []byte) error {
+	k.file.mu.Lock()
+	delete(k.file.data[k.namespace], string(key))
+	err := k.file.flush()
+	k.file.mu.Unlock()
+	return err
+}
+
+func (k *legacyKV) MigrateTo() (*Meta, error) {
+	return exportMeta(k)
+}
+
+func (k *legacyKV) MigrateFrom(meta *Meta) error {
+	return importMeta(k, meta)
+}
+
+func (k *legacyKV) Close() error {
+	return nil
+}
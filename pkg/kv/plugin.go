@@ -0,0 +1,102 @@
+package kv
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/go-faster/errors"
+)
+
+// Meta is the portable representation produced by KV.MigrateTo and consumed
+// by KV.MigrateFrom: every namespace's keys and values, engine-agnostic.
+type Meta struct {
+	Namespaces map[string]map[string][]byte
+}
+
+// Factory builds a KV engine from its driver-specific options, e.g. the
+// "bucket"/"prefix"/"path" keys parsed out of the --storage flag.
+type Factory func(options map[string]string) (KV, error)
+
+var (
+	driversMu sync.RWMutex
+	drivers   = map[DriverType]Factory{}
+)
+
+// Register makes a driver available under the given type, so it can be
+// selected via `--storage type=<name>,...`. It's meant to be called from the
+// init() of the package implementing the driver (see s3.go, gcs.go, redis.go,
+// postgres.go for examples), mirroring how database/sql drivers register
+// themselves.
+func Register(typ DriverType, factory Factory) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+
+	if factory == nil {
+		panic("kv: Register factory is nil")
+	}
+	if _, dup := drivers[typ]; dup {
+		panic(fmt.Sprintf("kv: Register called twice for driver %q", typ))
+	}
+	drivers[typ] = factory
+}
+
+func newRegistered(typ DriverType, options map[string]string) (KV, error) {
+	driversMu.RLock()
+	factory, ok := drivers[typ]
+	driversMu.RUnlock()
+
+	if !ok {
+		return nil, errors.Errorf("unknown storage driver: %s", typ)
+	}
+	return factory(options)
+}
+
+// exportMeta dumps every namespace of a KV engine into a Meta, for drivers
+// that don't have a cheaper native snapshot mechanism.
+func exportMeta(k KV) (*Meta, error) {
+	namespaces, err := k.Namespaces()
+	if err != nil {
+		return nil, errors.Wrap(err, "list namespaces")
+	}
+
+	meta := &Meta{Namespaces: make(map[string]map[string][]byte, len(namespaces))}
+	for _, ns := range namespaces {
+		h, err := k.Open(ns)
+		if err != nil {
+			return nil, errors.Wrapf(err, "open namespace %q", ns)
+		}
+
+		keys, err := h.Keys()
+		if err != nil {
+			return nil, errors.Wrapf(err, "list keys for %q", ns)
+		}
+
+		kvs := make(map[string][]byte, len(keys))
+		for _, key := range keys {
+			v, err := h.Get(key)
+			if err != nil {
+				return nil, errors.Wrapf(err, "get %q/%s", ns, key)
+			}
+			kvs[string(key)] = v
+		}
+		meta.Namespaces[ns] = kvs
+	}
+	return meta, nil
+}
+
+// importMeta replays a Meta produced by exportMeta (or another driver's
+// MigrateTo) into a KV engine.
+func importMeta(k KV, meta *Meta) error {
+	for ns, kvs := range meta.Namespaces {
+		h, err := k.Open(ns)
+		if err != nil {
+			return errors.Wrapf(err, "open namespace %q", ns)
+		}
+		for key, value := range kvs {
+			if err = h.Set([]byte(key), value); err != nil {
+				return errors.Wrapf(err, "set %q/%s", ns, key)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package kv
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-faster/errors"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// DriverPostgres stores namespaces/keys/values as rows in a single table,
+// created on first use if it doesn't already exist.
+const DriverPostgres DriverType = "postgres"
+
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS tdl_kv (
+	namespace TEXT NOT NULL,
+	key       TEXT NOT NULL,
+	value     BYTEA NOT NULL,
+	PRIMARY KEY (namespace, key)
+)`
+
+func init() {
+	Register(DriverPostgres, newPostgresKV)
+}
+
+type postgresKV struct {
+	db        *sql.DB
+	namespace string
+}
+
+// newPostgresKV builds a KV engine backed by a Postgres table. Required
+// option: dsn.
+func newPostgresKV(options map[string]string) (KV, error) {
+	dsn, ok := options["dsn"]
+	if !ok || dsn == "" {
+		return nil, errors.New("postgres storage requires a 'dsn' option")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, errors.Wrap(err, "open postgres")
+	}
+	if _, err = db.Exec(postgresSchema); err != nil {
+		return nil, errors.Wrap(err, "ensure schema")
+	}
+
+	return &postgresKV{db: db}, nil
+}
+
+func (k *postgresKV) Open(namespace string) (KV, error) {
+	ns := *k
+	ns.namespace = namespace
+	return &ns, nil
+}
+
+func (k *postgresKV) Namespaces() ([]string, error) {
+	rows, err := k.db.Query(`SELECT DISTINCT namespace FROM tdl_kv`)
+	if err != nil {
+		return nil, errors.Wrap(err, "query namespaces")
+	}
+	defer rows.Close()
+
+	var ns []string
+	for rows.Next() {
+		var n string
+		if err = rows.Scan(&n); err != nil {
+			return nil, errors.Wrap(err, "scan namespace")
+		}
+		ns = append(ns, n)
+	}
+	return ns, errors.Wrap(rows.Err(), "iterate namespaces")
+}
+
+func (k *postgresKV) Keys() ([][]byte, error) {
+	rows, err := k.db.Query(`SELECT key FROM tdl_kv WHERE namespace = $1`, k.namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "query keys")
+	}
+	defer rows.Close()
+
+	var keys [][]byte
+	for rows.Next() {
+		var key string
+		if err = rows.Scan(&key); err != nil {
+			return nil, errors.Wrap(err, "scan key")
+		}
+		keys = append(keys, []byte(key))
+	}
+	return keys, errors.Wrap(rows.Err(), "iterate keys")
+}
+
+func (k *postgresKV) Get(key []byte) ([]byte, error) {
+	var value []byte
+	err := k.db.QueryRow(`SELECT value FROM tdl_kv WHERE namespace = $1 AND key = $2`, k.namespace, string(key)).Scan(&value)
+	return value, errors.Wrap(err, "query value")
+}
+
+func (k *postgresKV) Set(key, value []byte) error {
+	_, err := k.db.ExecContext(context.Background(),
+		`INSERT INTO tdl_kv (namespace, key, value) VALUES ($1, $2, $3)
+		 ON CONFLICT (namespace, key) DO UPDATE SET value = EXCLUDED.value`,
+		k.namespace, string(key), value)
+	return errors.Wrap(err, "upsert value")
+}
+
+func (k *postgresKV) Delete(key []byte) error {
+	_, err := k.db.Exec(`DELETE FROM tdl_kv WHERE namespace = $1 AND key = $2`, k.namespace, string(key))
+	return errors.Wrap(err, "delete value")
+}
+
+func (k *postgresKV) MigrateTo() (*Meta, error) {
+	return exportMeta(k)
+}
+
+func (k *postgresKV) MigrateFrom(meta *Meta) error {
+	return importMeta(k, meta)
+}
+
+func (k *postgresKV) Close() error {
+	return k.db.Close()
+}
@@ -0,0 +1,113 @@
+package kv
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-faster/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// DriverRedis stores namespaces as Redis hashes, one hash per namespace
+// keyed "<prefix>:<namespace>", field = key, value = raw bytes.
+const DriverRedis DriverType = "redis"
+
+func init() {
+	Register(DriverRedis, newRedisKV)
+}
+
+type redisKV struct {
+	client    *redis.Client
+	prefix    string
+	namespace string
+}
+
+// newRedisKV builds a KV engine backed by Redis. Required option: addr.
+// Optional: password, db, prefix (defaults to "tdl").
+func newRedisKV(options map[string]string) (KV, error) {
+	addr, ok := options["addr"]
+	if !ok || addr == "" {
+		return nil, errors.New("redis storage requires an 'addr' option")
+	}
+
+	prefix := options["prefix"]
+	if prefix == "" {
+		prefix = "tdl"
+	}
+
+	db := 0
+	if raw, ok := options["db"]; ok && raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid 'db' option %q", raw)
+		}
+		db = parsed
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: options["password"],
+		DB:       db,
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, errors.Wrap(err, "ping redis")
+	}
+
+	return &redisKV{client: client, prefix: prefix}, nil
+}
+
+func (k *redisKV) hashKey() string {
+	return k.prefix + ":" + k.namespace
+}
+
+func (k *redisKV) Open(namespace string) (KV, error) {
+	ns := *k
+	ns.namespace = namespace
+	return &ns, nil
+}
+
+func (k *redisKV) Namespaces() ([]string, error) {
+	var ns []string
+	iter := k.client.Scan(context.Background(), 0, k.prefix+":*", 0).Iterator()
+	for iter.Next(context.Background()) {
+		ns = append(ns, iter.Val()[len(k.prefix)+1:])
+	}
+	return ns, errors.Wrap(iter.Err(), "scan keys")
+}
+
+func (k *redisKV) Keys() ([][]byte, error) {
+	fields, err := k.client.HKeys(context.Background(), k.hashKey()).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "hkeys")
+	}
+	keys := make([][]byte, len(fields))
+	for i, f := range fields {
+		keys[i] = []byte(f)
+	}
+	return keys, nil
+}
+
+func (k *redisKV) Get(key []byte) ([]byte, error) {
+	v, err := k.client.HGet(context.Background(), k.hashKey(), string(key)).Bytes()
+	return v, errors.Wrap(err, "hget")
+}
+
+func (k *redisKV) Set(key, value []byte) error {
+	return errors.Wrap(k.client.HSet(context.Background(), k.hashKey(), string(key), value).Err(), "hset")
+}
+
+func (k *redisKV) Delete(key []byte) error {
+	return errors.Wrap(k.client.HDel(context.Background(), k.hashKey(), string(key)).Err(), "hdel")
+}
+
+func (k *redisKV) MigrateTo() (*Meta, error) {
+	return exportMeta(k)
+}
+
+func (k *redisKV) MigrateFrom(meta *Meta) error {
+	return importMeta(k, meta)
+}
+
+func (k *redisKV) Close() error {
+	return k.client.Close()
+}
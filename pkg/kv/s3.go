@@ -0,0 +1,167 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/go-faster/errors"
+)
+
+// DriverS3 stores each namespace as a tree of objects under bucket/prefix,
+// one object per key. Useful for sharing sessions across machines without
+// standing up a dedicated database.
+const DriverS3 DriverType = "s3"
+
+func init() {
+	Register(DriverS3, newS3KV)
+}
+
+type s3KV struct {
+	client    *s3.Client
+	bucket    string
+	prefix    string
+	namespace string
+}
+
+// newS3KV builds a KV engine backed by an S3-compatible bucket. Required
+// option: bucket. Optional: prefix, region, endpoint (for S3-compatible
+// services such as R2 or MinIO).
+func newS3KV(options map[string]string) (KV, error) {
+	bucket, ok := options["bucket"]
+	if !ok || bucket == "" {
+		return nil, errors.New("s3 storage requires a 'bucket' option")
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(err, "load aws config")
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint, ok := options["endpoint"]; ok && endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+		if region, ok := options["region"]; ok && region != "" {
+			o.Region = region
+		}
+	})
+
+	return &s3KV{client: client, bucket: bucket, prefix: options["prefix"]}, nil
+}
+
+// rootPrefix returns the configured prefix with a trailing separator, or ""
+// when no prefix is set. Building prefixes this way (instead of path.Join,
+// which collapses "" away to "." and strips trailing separators) keeps the
+// "/" boundary between prefix and namespace explicit and never produces a
+// bare "/" when prefix is unset.
+func (k *s3KV) rootPrefix() string {
+	if k.prefix == "" {
+		return ""
+	}
+	return k.prefix + "/"
+}
+
+func (k *s3KV) namespacePrefix() string {
+	return k.rootPrefix() + k.namespace + "/"
+}
+
+func (k *s3KV) objectKey(key string) string {
+	return k.namespacePrefix() + key
+}
+
+func (k *s3KV) Open(namespace string) (KV, error) {
+	ns := *k
+	ns.namespace = namespace
+	return &ns, nil
+}
+
+func (k *s3KV) Namespaces() ([]string, error) {
+	seen := map[string]struct{}{}
+	var ns []string
+
+	root := k.rootPrefix()
+	paginator := s3.NewListObjectsV2Paginator(k.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(k.bucket),
+		Prefix: aws.String(root),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, errors.Wrap(err, "list objects")
+		}
+		for _, obj := range page.Contents {
+			rel := bytes.TrimPrefix([]byte(aws.ToString(obj.Key)), []byte(root))
+			if i := bytes.IndexByte(rel, '/'); i >= 0 {
+				name := string(rel[:i])
+				if _, ok := seen[name]; !ok {
+					seen[name] = struct{}{}
+					ns = append(ns, name)
+				}
+			}
+		}
+	}
+	return ns, nil
+}
+
+func (k *s3KV) Keys() ([][]byte, error) {
+	var keys [][]byte
+	prefix := k.namespacePrefix()
+
+	paginator := s3.NewListObjectsV2Paginator(k.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(k.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, errors.Wrap(err, "list objects")
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, bytes.TrimPrefix([]byte(aws.ToString(obj.Key)), []byte(prefix)))
+		}
+	}
+	return keys, nil
+}
+
+func (k *s3KV) Get(key []byte) (value []byte, err error) {
+	out, err := k.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(k.bucket),
+		Key:    aws.String(k.objectKey(string(key))),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "get object")
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+func (k *s3KV) Set(key, value []byte) error {
+	_, err := k.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(k.bucket),
+		Key:    aws.String(k.objectKey(string(key))),
+		Body:   bytes.NewReader(value),
+	})
+	return errors.Wrap(err, "put object")
+}
+
+func (k *s3KV) Delete(key []byte) error {
+	_, err := k.client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: aws.String(k.bucket),
+		Key:    aws.String(k.objectKey(string(key))),
+	})
+	return errors.Wrap(err, "delete object")
+}
+
+func (k *s3KV) MigrateTo() (*Meta, error) {
+	return exportMeta(k)
+}
+
+func (k *s3KV) MigrateFrom(meta *Meta) error {
+	return importMeta(k, meta)
+}
+
+func (k *s3KV) Close() error { return nil }
@@ -0,0 +1,24 @@
+package kv
+
+import "testing"
+
+func TestS3KVObjectKey(t *testing.T) {
+	cases := []struct {
+		prefix, namespace, key string
+		wantNamespacePrefix    string
+		wantObjectKey          string
+	}{
+		{prefix: "", namespace: "default", key: "a", wantNamespacePrefix: "default/", wantObjectKey: "default/a"},
+		{prefix: "tdl", namespace: "default", key: "a", wantNamespacePrefix: "tdl/default/", wantObjectKey: "tdl/default/a"},
+	}
+
+	for _, c := range cases {
+		k := &s3KV{prefix: c.prefix, namespace: c.namespace}
+		if got := k.namespacePrefix(); got != c.wantNamespacePrefix {
+			t.Errorf("namespacePrefix(prefix=%q) = %q, want %q", c.prefix, got, c.wantNamespacePrefix)
+		}
+		if got := k.objectKey(c.key); got != c.wantObjectKey {
+			t.Errorf("objectKey(prefix=%q, key=%q) = %q, want %q", c.prefix, c.key, got, c.wantObjectKey)
+		}
+	}
+}
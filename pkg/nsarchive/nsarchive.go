@@ -0,0 +1,170 @@
+// Package nsarchive implements portable, passphrase-encrypted backups of a
+// single KV namespace, used by `tdl namespace export/import` and internally
+// by `tdl namespace rename`.
+package nsarchive
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+
+	"github.com/go-faster/errors"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/iyear/tdl/pkg/kv"
+)
+
+const (
+	saltSize  = 16
+	nonceSize = 12
+	scryptN   = 1 << 15
+	scryptR   = 8
+	scryptP   = 1
+	keySize   = 32
+)
+
+// Copy duplicates every key of namespace src into dst within the same
+// engine, without going through encryption — used by `tdl namespace rename`.
+func Copy(engine kv.KV, src, dst string) error {
+	kvs, err := dump(engine, src)
+	if err != nil {
+		return errors.Wrap(err, "dump source namespace")
+	}
+
+	h, err := engine.Open(dst)
+	if err != nil {
+		return errors.Wrap(err, "open destination namespace")
+	}
+	for key, value := range kvs {
+		if err = h.Set([]byte(key), value); err != nil {
+			return errors.Wrapf(err, "set key %q", key)
+		}
+	}
+	return nil
+}
+
+// Export writes namespace's contents to path, encrypted with a key derived
+// from passphrase via scrypt.
+func Export(engine kv.KV, namespace, path, passphrase string) error {
+	kvs, err := dump(engine, namespace)
+	if err != nil {
+		return errors.Wrap(err, "dump namespace")
+	}
+
+	plaintext, err := json.Marshal(kvs)
+	if err != nil {
+		return errors.Wrap(err, "marshal namespace")
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err = rand.Read(salt); err != nil {
+		return errors.Wrap(err, "generate salt")
+	}
+
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return errors.Wrap(err, "init cipher")
+	}
+
+	nonce := make([]byte, nonceSize)
+	if _, err = rand.Read(nonce); err != nil {
+		return errors.Wrap(err, "generate nonce")
+	}
+
+	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return errors.Wrap(err, "create archive file")
+	}
+	defer f.Close()
+
+	if _, err = f.Write(salt); err != nil {
+		return errors.Wrap(err, "write salt")
+	}
+	if _, err = f.Write(nonce); err != nil {
+		return errors.Wrap(err, "write nonce")
+	}
+	if _, err = f.Write(ciphertext); err != nil {
+		return errors.Wrap(err, "write ciphertext")
+	}
+	return nil
+}
+
+// Import decrypts the archive at path with passphrase and writes its
+// contents into namespace.
+func Import(engine kv.KV, path, namespace, passphrase string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "read archive file")
+	}
+	if len(raw) < saltSize+nonceSize {
+		return errors.New("archive file is truncated or not a tdl namespace archive")
+	}
+
+	salt, nonce, ciphertext := raw[:saltSize], raw[saltSize:saltSize+nonceSize], raw[saltSize+nonceSize:]
+
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return errors.Wrap(err, "init cipher")
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return errors.Wrap(err, "decrypt archive, wrong passphrase?")
+	}
+
+	var kvs map[string][]byte
+	if err = json.Unmarshal(plaintext, &kvs); err != nil {
+		return errors.Wrap(err, "unmarshal namespace")
+	}
+
+	h, err := engine.Open(namespace)
+	if err != nil {
+		return errors.Wrap(err, "open destination namespace")
+	}
+	for key, value := range kvs {
+		if err = h.Set([]byte(key), value); err != nil {
+			return errors.Wrapf(err, "set key %q", key)
+		}
+	}
+	return nil
+}
+
+func dump(engine kv.KV, namespace string) (map[string][]byte, error) {
+	h, err := engine.Open(namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "open namespace")
+	}
+
+	keys, err := h.Keys()
+	if err != nil {
+		return nil, errors.Wrap(err, "list keys")
+	}
+
+	kvs := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		value, err := h.Get(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get key %q", key)
+		}
+		kvs[string(key)] = value
+	}
+	return kvs, nil
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, errors.Wrap(err, "derive key")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "new aes cipher")
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,102 @@
+package nsarchive
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/iyear/tdl/pkg/kv"
+)
+
+// memKV is a minimal in-memory kv.KV used to exercise nsarchive without a
+// real storage driver.
+type memKV struct {
+	namespace string
+	data      map[string]map[string][]byte
+}
+
+func newMemKV() *memKV {
+	return &memKV{data: make(map[string]map[string][]byte)}
+}
+
+func (m *memKV) Open(namespace string) (kv.KV, error) {
+	ns := &memKV{namespace: namespace, data: m.data}
+	if _, ok := ns.data[namespace]; !ok {
+		ns.data[namespace] = make(map[string][]byte)
+	}
+	return ns, nil
+}
+
+func (m *memKV) Namespaces() ([]string, error) {
+	var ns []string
+	for name := range m.data {
+		ns = append(ns, name)
+	}
+	return ns, nil
+}
+
+func (m *memKV) Keys() ([][]byte, error) {
+	var keys [][]byte
+	for key := range m.data[m.namespace] {
+		keys = append(keys, []byte(key))
+	}
+	return keys, nil
+}
+
+func (m *memKV) Get(key []byte) ([]byte, error) { return m.data[m.namespace][string(key)], nil }
+
+func (m *memKV) Set(key, value []byte) error {
+	m.data[m.namespace][string(key)] = value
+	return nil
+}
+
+func (m *memKV) Delete(key []byte) error {
+	delete(m.data[m.namespace], string(key))
+	return nil
+}
+
+func (m *memKV) MigrateTo() (*kv.Meta, error)    { return nil, nil }
+func (m *memKV) MigrateFrom(meta *kv.Meta) error { return nil }
+func (m *memKV) Close() error                    { return nil }
+
+func TestExportImportRoundTrip(t *testing.T) {
+	engine := newMemKV()
+	h, err := engine.Open("default")
+	if err != nil {
+		t.Fatalf("open namespace: %v", err)
+	}
+	if err = h.Set([]byte("a"), []byte("1")); err != nil {
+		t.Fatalf("set a: %v", err)
+	}
+	if err = h.Set([]byte("b"), []byte("2")); err != nil {
+		t.Fatalf("set b: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "archive.tdl")
+	if err = Export(engine, "default", path, "correct horse battery staple"); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	if err = Import(engine, path, "restored", "correct horse battery staple"); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	restored, err := engine.Open("restored")
+	if err != nil {
+		t.Fatalf("open restored namespace: %v", err)
+	}
+	got, err := restored.Get([]byte("a"))
+	if err != nil || string(got) != "1" {
+		t.Errorf("restored key %q = %q, %v, want %q, nil", "a", got, err, "1")
+	}
+
+	if err = Import(engine, path, "restored2", "wrong passphrase"); err == nil {
+		t.Errorf("import with wrong passphrase: want error, got nil")
+	}
+}
+
+func TestImportMissingArchive(t *testing.T) {
+	engine := newMemKV()
+	if err := Import(engine, filepath.Join(t.TempDir(), "nope.tdl"), "ns", "pw"); err == nil {
+		t.Errorf("import of missing file: want error, got nil")
+	}
+}